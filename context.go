@@ -5,6 +5,7 @@ package ctxkey
 import (
 	"context"
 	"fmt"
+	"reflect"
 )
 
 // ContextWith is an interface for producting functions that can add a value to
@@ -19,18 +20,51 @@ type ContextSet[V any] interface {
 	Set(ctx context.Context, val V) context.Context
 }
 
+// valueBox wraps a value stored in a context.Context so that the dynamic
+// type recorded by context.WithValue is always *valueBox[V], never V
+// itself. This keeps the type assertion performed on read unambiguous even
+// when V is an interface type, and lets a stored nil interface value be
+// distinguished from "no value was ever set".
+type valueBox[V any] struct {
+	v V
+}
+
 // Key is a type that is used as a key in a context.Context for a
 // specific type of value V.
-type Key[V any] struct{}
+//
+// Key carries a guard field so that it's never zero-sized: Go's allocator
+// gives every zero-sized value the same address, which would make all
+// *Key[V] instances for a given V compare equal as context keys.
+type Key[V any] struct {
+	_ byte
+}
 
 // New creates a new Key
-func New[V any]() *Key[V] {
-	return &Key[V]{}
+func New[V any](opts ...Option) *Key[V] {
+	k := &Key[V]{}
+	registerKey(registeredKey{
+		name: resolveName(opts, k),
+		typ:  reflect.TypeOf((*V)(nil)).Elem(),
+		copy: func(src, dst context.Context, _ detachOptions) context.Context {
+			if v, ok := k.Value(src); ok {
+				dst = k.Set(dst, v)
+			}
+			return dst
+		},
+		value: func(ctx context.Context) (any, bool) {
+			v, ok := k.Value(ctx)
+			if !ok {
+				return nil, false
+			}
+			return v, true
+		},
+	})
+	return k
 }
 
 // Set adds a value to the context for this key.
 func (k *Key[V]) Set(ctx context.Context, val V) context.Context {
-	return context.WithValue(ctx, k, val)
+	return context.WithValue(ctx, k, &valueBox[V]{v: val})
 }
 
 // With returns a fn that adds a value to the context for this key.
@@ -43,8 +77,12 @@ func (k *Key[V]) With(val V) func(ctx context.Context) context.Context {
 // Value retrieves the value from the context for this key. It returns the value
 // and a boolean indicating if the value was found.
 func (k *Key[V]) Value(ctx context.Context) (V, bool) {
-	v, ok := ctx.Value(k).(V)
-	return v, ok
+	b, ok := ctx.Value(k).(*valueBox[V])
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return b.v, true
 }
 
 // MustValue retrieves the value from the context for this key. It panics if the
@@ -57,22 +95,86 @@ func (k *Key[V]) MustValue(ctx context.Context) V {
 	return v
 }
 
+// ValueKey is a value-typed alternative to Key. Unlike Key, which must be
+// allocated with New and referenced by pointer so that each instance has a
+// distinct identity, a ValueKey can be declared directly as a package-level
+// var (e.g. `var CtxUser = ctxkey.ValueKey[User]{}`) with no allocation.
+// The tradeoff is that two independently-constructed ValueKey[V] values for
+// the same V are indistinguishable from each other, since context keys are
+// compared by type and value and ValueKey[V] carries no state of its own.
+// Use distinct V types (e.g. a defined type per key) if more than one
+// ValueKey is needed for the same underlying value type.
+type ValueKey[V any] struct{}
+
+// Set adds a value to the context for this key.
+func (k ValueKey[V]) Set(ctx context.Context, val V) context.Context {
+	return context.WithValue(ctx, k, &valueBox[V]{v: val})
+}
+
+// With returns a fn that adds a value to the context for this key.
+func (k ValueKey[V]) With(val V) func(ctx context.Context) context.Context {
+	return func(ctx context.Context) context.Context {
+		return k.Set(ctx, val)
+	}
+}
+
+// Value retrieves the value from the context for this key. It returns the value
+// and a boolean indicating if the value was found.
+func (k ValueKey[V]) Value(ctx context.Context) (V, bool) {
+	b, ok := ctx.Value(k).(*valueBox[V])
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return b.v, true
+}
+
+// MustValue retrieves the value from the context for this key. It panics if the
+// value is not found.
+func (k ValueKey[V]) MustValue(ctx context.Context) V {
+	v, ok := k.Value(ctx)
+	if !ok {
+		panic(fmt.Sprintf("could not find value for key %T in context", k))
+	}
+	return v
+}
+
 // DefaultingKey is a type that is used as a key in a context.Context for
 // a specific type of value, but returns the default value for V if unset.
 type DefaultingKey[V comparable] struct {
+	// guard ensures the struct is never zero-sized even when V is (see Key).
+	guard        byte
 	defaultValue V
 }
 
 // NewWithDefault creates a new DefaultingKey with the given default value
-func NewWithDefault[V comparable](defaultValue V) *DefaultingKey[V] {
-	return &DefaultingKey[V]{
+func NewWithDefault[V comparable](defaultValue V, opts ...Option) *DefaultingKey[V] {
+	k := &DefaultingKey[V]{
 		defaultValue: defaultValue,
 	}
+	registerKey(registeredKey{
+		name: resolveName(opts, k),
+		typ:  reflect.TypeOf((*V)(nil)).Elem(),
+		copy: func(src, dst context.Context, _ detachOptions) context.Context {
+			if v, ok := k.valueOk(src); ok {
+				dst = k.Set(dst, v)
+			}
+			return dst
+		},
+		value: func(ctx context.Context) (any, bool) {
+			v, ok := k.valueOk(ctx)
+			if !ok {
+				return nil, false
+			}
+			return v, true
+		},
+	})
+	return k
 }
 
 // Set adds a value to the context for this key.
 func (k *DefaultingKey[V]) Set(ctx context.Context, val V) context.Context {
-	return context.WithValue(ctx, k, val)
+	return context.WithValue(ctx, k, &valueBox[V]{v: val})
 }
 
 // With returns a fn that adds a value to the context for this key.
@@ -85,13 +187,24 @@ func (k *DefaultingKey[V]) With(val V) func(ctx context.Context) context.Context
 // Value retrieves the value from the context for this key. If the value is not
 // found, it returns the default value.
 func (k *DefaultingKey[V]) Value(ctx context.Context) V {
-	v, ok := ctx.Value(k).(V)
+	v, ok := k.valueOk(ctx)
 	if !ok {
 		return k.defaultValue
 	}
 	return v
 }
 
+// valueOk is like Value, but also reports whether a value was actually set,
+// as opposed to the default value being returned.
+func (k *DefaultingKey[V]) valueOk(ctx context.Context) (V, bool) {
+	b, ok := ctx.Value(k).(*valueBox[V])
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return b.v, true
+}
+
 // MustNonEmptyValue retrieves the value from the context for this key. If the value is
 // empty, it panics.
 func (k *DefaultingKey[V]) MustNonEmptyValue(ctx context.Context) V {
@@ -114,14 +227,38 @@ type Box[V any] struct {
 // It can also be used to hold non-comparable objects by wrapping them with a
 // pointer.
 type BoxedKey[V any] struct {
+	// guard ensures the struct is never zero-sized even when V is (see Key).
+	guard        byte
 	defaultValue V
 }
 
 // NewBoxedWithDefault creates a new BoxedKey with a default value
-func NewBoxedWithDefault[V any](defaultValue V) *BoxedKey[V] {
-	return &BoxedKey[V]{
+func NewBoxedWithDefault[V any](defaultValue V, opts ...Option) *BoxedKey[V] {
+	k := &BoxedKey[V]{
 		defaultValue: defaultValue,
 	}
+	registerKey(registeredKey{
+		name: resolveName(opts, k),
+		typ:  reflect.TypeOf((*V)(nil)).Elem(),
+		copy: func(src, dst context.Context, opts detachOptions) context.Context {
+			b, ok := k.box(src)
+			if !ok {
+				return dst
+			}
+			if opts.shareBoxes {
+				return context.WithValue(dst, k, b)
+			}
+			return context.WithValue(dst, k, &Box[V]{value: b.value})
+		},
+		value: func(ctx context.Context) (any, bool) {
+			b, ok := k.box(ctx)
+			if !ok {
+				return nil, false
+			}
+			return b.value, true
+		},
+	})
+	return k
 }
 
 // Set adds a boxed value to the context for this key.
@@ -164,6 +301,12 @@ func (k *BoxedKey[V]) Value(ctx context.Context) V {
 	return handle.value
 }
 
+// box returns the *Box[V] handle stored in ctx for this key, if any.
+func (k *BoxedKey[V]) box(ctx context.Context) (*Box[V], bool) {
+	b, ok := ctx.Value(k).(*Box[V])
+	return b, ok
+}
+
 // With takes a list of functions that modify a context and returns a new
 // function that applies all of them. This can be used with the `.With(value)`
 // methods on keys to apply multiple values to a context.
@@ -190,6 +190,53 @@ func TestNewBoxedWithDefault(t *testing.T) {
 	}
 }
 
+func TestNewInterfaceValue(t *testing.T) {
+	ctxKey := New[error]()
+	ctx := context.Background()
+
+	value, ok := ctxKey.Value(ctx)
+	if ok || value != nil {
+		t.Fatal("expected no value")
+	}
+
+	ctx = ctxKey.Set(ctx, nil)
+	value, ok = ctxKey.Value(ctx)
+	if !ok {
+		t.Fatal("expected ok for a stored nil interface value")
+	}
+	if value != nil {
+		t.Fatal("expected nil value")
+	}
+
+	err := fmt.Errorf("boom")
+	ctx = ctxKey.Set(ctx, err)
+	if value, ok := ctxKey.Value(ctx); !ok || value != err {
+		t.Fatal("expected stored error")
+	}
+}
+
+func TestValueKey(t *testing.T) {
+	type user struct{ name string }
+
+	ctxKey := ValueKey[user]{}
+	ctx := context.Background()
+
+	expectPanic(t, func() {
+		_ = ctxKey.MustValue(ctx)
+	})
+
+	ctx = ctxKey.Set(ctx, user{name: "alice"})
+	if ctxKey.MustValue(ctx).name != "alice" {
+		t.Fatal("expected value")
+	}
+
+	// two independently-constructed ValueKeys of the same V are the same key
+	otherKey := ValueKey[user]{}
+	if value, ok := otherKey.Value(ctx); !ok || value.name != "alice" {
+		t.Fatal("expected independently-constructed ValueKey of the same type to see the same value")
+	}
+}
+
 func TestWith(t *testing.T) {
 	key1 := New[string]()
 	key2 := NewWithDefault[string]("")
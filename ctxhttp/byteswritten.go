@@ -0,0 +1,154 @@
+package ctxhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/authzed/ctxkey"
+)
+
+// BytesWritten is a context key holding the number of response bytes
+// written so far, populated by BytesWrittenMiddleware.
+var BytesWritten = ctxkey.NewBoxedWithDefault[int64](0)
+
+// StatusCode is a context key holding the response status code, populated
+// by BytesWrittenMiddleware. It defaults to http.StatusOK, matching the
+// status net/http assumes when a handler writes a body without first
+// calling WriteHeader.
+var StatusCode = ctxkey.NewBoxedWithDefault(http.StatusOK)
+
+// BytesWrittenMiddleware carves out boxes for BytesWritten and StatusCode
+// in the request context, and wraps the http.ResponseWriter so that
+// writes made anywhere downstream fill them in. Because the boxes are
+// shared, a handler further up the chain can inspect the final values
+// after next has returned.
+func BytesWrittenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := BytesWritten.SetBox(r.Context())
+		ctx = StatusCode.SetBox(ctx)
+
+		rw := wrapResponseWriter(w, ctx)
+		next.ServeHTTP(rw, r.WithContext(ctx))
+	})
+}
+
+// countingResponseWriter wraps an http.ResponseWriter, recording the
+// status code and running total of bytes written into the request's
+// context via BytesWritten and StatusCode.
+type countingResponseWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	wroteHeader bool
+}
+
+func (w *countingResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		StatusCode.Set(w.ctx, status)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *countingResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		StatusCode.Set(w.ctx, http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	BytesWritten.Set(w.ctx, BytesWritten.Value(w.ctx)+int64(n))
+	return n, err
+}
+
+// readFrom implements io.ReaderFrom by delegating to the wrapped
+// ResponseWriter's own ReadFrom, counting the bytes it reports. Callers
+// must only invoke this when the wrapped ResponseWriter implements
+// io.ReaderFrom.
+func (w *countingResponseWriter) readFrom(r io.Reader) (int64, error) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		StatusCode.Set(w.ctx, http.StatusOK)
+	}
+	n, err := w.ResponseWriter.(io.ReaderFrom).ReadFrom(r)
+	BytesWritten.Set(w.ctx, BytesWritten.Value(w.ctx)+n)
+	return n, err
+}
+
+// wrapResponseWriter wraps w in a countingResponseWriter, choosing a
+// wrapper type that implements the same combination of http.Flusher,
+// http.Hijacker, and io.ReaderFrom that w itself implements. Without this,
+// wrapping a ResponseWriter that supports one of those optional interfaces
+// (needed for SSE/streaming handlers or websocket upgrades) would silently
+// hide it from everything downstream of this middleware.
+func wrapResponseWriter(w http.ResponseWriter, ctx context.Context) http.ResponseWriter {
+	base := &countingResponseWriter{ResponseWriter: w, ctx: ctx}
+
+	flusher, isFlusher := w.(http.Flusher)
+	hijacker, isHijacker := w.(http.Hijacker)
+	_, isReaderFrom := w.(io.ReaderFrom)
+
+	switch {
+	case isFlusher && isHijacker && isReaderFrom:
+		return &flusherHijackerReaderFromWriter{countingResponseWriter: base, Flusher: flusher, Hijacker: hijacker}
+	case isFlusher && isHijacker:
+		return &flusherHijackerWriter{countingResponseWriter: base, Flusher: flusher, Hijacker: hijacker}
+	case isFlusher && isReaderFrom:
+		return &flusherReaderFromWriter{countingResponseWriter: base, Flusher: flusher}
+	case isHijacker && isReaderFrom:
+		return &hijackerReaderFromWriter{countingResponseWriter: base, Hijacker: hijacker}
+	case isFlusher:
+		return &flusherWriter{countingResponseWriter: base, Flusher: flusher}
+	case isHijacker:
+		return &hijackerWriter{countingResponseWriter: base, Hijacker: hijacker}
+	case isReaderFrom:
+		return &readerFromWriter{countingResponseWriter: base}
+	default:
+		return base
+	}
+}
+
+type flusherWriter struct {
+	*countingResponseWriter
+	http.Flusher
+}
+
+type hijackerWriter struct {
+	*countingResponseWriter
+	http.Hijacker
+}
+
+type readerFromWriter struct {
+	*countingResponseWriter
+}
+
+func (w *readerFromWriter) ReadFrom(r io.Reader) (int64, error) { return w.readFrom(r) }
+
+type flusherHijackerWriter struct {
+	*countingResponseWriter
+	http.Flusher
+	http.Hijacker
+}
+
+type flusherReaderFromWriter struct {
+	*countingResponseWriter
+	http.Flusher
+}
+
+func (w *flusherReaderFromWriter) ReadFrom(r io.Reader) (int64, error) { return w.readFrom(r) }
+
+type hijackerReaderFromWriter struct {
+	*countingResponseWriter
+	http.Hijacker
+}
+
+func (w *hijackerReaderFromWriter) ReadFrom(r io.Reader) (int64, error) { return w.readFrom(r) }
+
+type flusherHijackerReaderFromWriter struct {
+	*countingResponseWriter
+	http.Flusher
+	http.Hijacker
+}
+
+func (w *flusherHijackerReaderFromWriter) ReadFrom(r io.Reader) (int64, error) {
+	return w.readFrom(r)
+}
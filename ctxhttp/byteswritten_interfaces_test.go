@@ -0,0 +1,109 @@
+package ctxhttp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// plainResponseWriter implements only http.ResponseWriter, none of the
+// optional interfaces.
+type plainResponseWriter struct {
+	header http.Header
+}
+
+func (w *plainResponseWriter) Header() http.Header         { return w.header }
+func (w *plainResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *plainResponseWriter) WriteHeader(int)             {}
+
+// fakeHijacker implements http.ResponseWriter and http.Hijacker.
+type fakeHijacker struct {
+	plainResponseWriter
+	hijacked bool
+}
+
+func (w *fakeHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+// fakeReaderFrom implements http.ResponseWriter and io.ReaderFrom.
+type fakeReaderFrom struct {
+	plainResponseWriter
+	written int64
+}
+
+func (w *fakeReaderFrom) ReadFrom(r io.Reader) (int64, error) {
+	n, err := io.Copy(io.Discard, r)
+	w.written += n
+	return n, err
+}
+
+func TestWrapResponseWriterHidesUnsupportedFlusher(t *testing.T) {
+	rw := wrapResponseWriter(&plainResponseWriter{header: http.Header{}}, context.Background())
+	if _, ok := rw.(http.Flusher); ok {
+		t.Fatal("expected wrapped writer to not implement http.Flusher")
+	}
+}
+
+func TestWrapResponseWriterPreservesFlusher(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := wrapResponseWriter(rec, context.Background())
+
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		t.Fatal("expected wrapped writer to implement http.Flusher")
+	}
+	flusher.Flush()
+	if !rec.Flushed {
+		t.Fatal("expected Flush to reach the underlying ResponseWriter")
+	}
+}
+
+func TestWrapResponseWriterPreservesHijacker(t *testing.T) {
+	underlying := &fakeHijacker{plainResponseWriter: plainResponseWriter{header: http.Header{}}}
+	rw := wrapResponseWriter(underlying, context.Background())
+
+	hijacker, ok := rw.(http.Hijacker)
+	if !ok {
+		t.Fatal("expected wrapped writer to implement http.Hijacker")
+	}
+	if _, _, err := hijacker.Hijack(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !underlying.hijacked {
+		t.Fatal("expected Hijack to reach the underlying ResponseWriter")
+	}
+}
+
+func TestWrapResponseWriterPreservesReaderFromAndCountsBytes(t *testing.T) {
+	underlying := &fakeReaderFrom{plainResponseWriter: plainResponseWriter{header: http.Header{}}}
+
+	ctx := BytesWritten.SetBox(context.Background())
+	ctx = StatusCode.SetBox(ctx)
+	rw := wrapResponseWriter(underlying, ctx)
+
+	readerFrom, ok := rw.(io.ReaderFrom)
+	if !ok {
+		t.Fatal("expected wrapped writer to implement io.ReaderFrom")
+	}
+
+	n, err := readerFrom.ReadFrom(bytes.NewReader([]byte("hello")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 5 {
+		t.Fatalf("expected 5 bytes read, got %d", n)
+	}
+	if underlying.written != 5 {
+		t.Fatal("expected ReadFrom to reach the underlying ResponseWriter")
+	}
+	if BytesWritten.Value(ctx) != 5 {
+		t.Fatalf("expected BytesWritten to be 5, got %d", BytesWritten.Value(ctx))
+	}
+}
@@ -0,0 +1,18 @@
+// Package ctxhttp provides reusable net/http middleware built on top of
+// ctxkey: request ID propagation, response byte/status accounting, and
+// helpers for composing middleware and seeding context values in tests.
+package ctxhttp
+
+import "net/http"
+
+// Chain composes mws into a single middleware that applies them in order,
+// so that Chain(a, b, c)(handler) behaves like a(b(c(handler))) and a
+// request flows through a, then b, then c, then handler.
+func Chain(mws ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
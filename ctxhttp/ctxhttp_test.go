@@ -0,0 +1,127 @@
+package ctxhttp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/authzed/ctxkey"
+)
+
+func TestRequestIDMiddlewareGeneratesID(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID.Value(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if seen == "" {
+		t.Fatal("expected a generated request id")
+	}
+	if rec.Header().Get("X-Request-Id") != seen {
+		t.Fatal("expected response header to echo the request id")
+	}
+}
+
+func TestRequestIDMiddlewareReusesHeader(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID.Value(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", "incoming-id")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if seen != "incoming-id" {
+		t.Fatalf("expected incoming-id, got %q", seen)
+	}
+	if rec.Header().Get("X-Request-Id") != "incoming-id" {
+		t.Fatal("expected response header to echo the incoming request id")
+	}
+}
+
+func TestRequestIDMiddlewareReusesTraceparent(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID.Value(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if seen != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected trace id from traceparent, got %q", seen)
+	}
+}
+
+func TestBytesWrittenMiddleware(t *testing.T) {
+	var bytesWritten int64
+	var status int
+	handler := BytesWrittenMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, "hello")
+
+		bytesWritten = BytesWritten.Value(r.Context())
+		status = StatusCode.Value(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if bytesWritten != 5 {
+		t.Fatalf("expected 5 bytes written, got %d", bytesWritten)
+	}
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", status)
+	}
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	mw := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	handler := Chain(mw("a"), mw("b"), mw("c"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	expected := []string{"a", "b", "c", "handler"}
+	if len(order) != len(expected) {
+		t.Fatalf("expected order %v, got %v", expected, order)
+	}
+	for i, name := range expected {
+		if order[i] != name {
+			t.Fatalf("expected order %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestInject(t *testing.T) {
+	ctxUser := ctxkey.New[string]()
+
+	var seen string
+	handler := Inject(ctxUser.With("alice"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = ctxUser.MustValue(r.Context())
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if seen != "alice" {
+		t.Fatalf("expected alice, got %q", seen)
+	}
+}
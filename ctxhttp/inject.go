@@ -0,0 +1,22 @@
+package ctxhttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/authzed/ctxkey"
+)
+
+// Inject returns middleware that applies sets (typically the result of
+// calling a key's With method, e.g. ctxAuthorizedUser.With(someUser)) to
+// the request's context before invoking the wrapped handler. It's meant
+// for tests, to seed the context values that production middleware would
+// otherwise have set.
+func Inject(sets ...func(context.Context) context.Context) func(http.Handler) http.Handler {
+	apply := ctxkey.With(sets...)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(apply(r.Context())))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package ctxhttp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/authzed/ctxkey"
+)
+
+// RequestID is a context key holding the ID of the in-flight request, set
+// by RequestIDMiddleware.
+var RequestID = ctxkey.NewWithDefault("")
+
+// RequestIDMiddleware ensures every request has an ID in its context. It
+// reuses the ID from the X-Request-Id or Traceparent request header if
+// either is present, otherwise it generates a new one. The ID is stored
+// under RequestID and echoed back in the X-Request-Id response header.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-Id")
+		if id == "" {
+			id = traceparentRequestID(r.Header.Get("Traceparent"))
+		}
+		if id == "" {
+			id = newRequestID()
+		}
+
+		w.Header().Set("X-Request-Id", id)
+		r = r.WithContext(RequestID.Set(r.Context(), id))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// traceparentRequestID extracts the trace ID portion of a W3C traceparent
+// header (version-traceid-spanid-flags): the second "-"-separated field of
+// tp, or "" if tp has no non-empty second field. The number of fields and
+// the length and encoding of each field are not checked.
+func traceparentRequestID(tp string) string {
+	parts := strings.Split(tp, "-")
+	if len(parts) < 2 || parts[1] == "" {
+		return ""
+	}
+	return parts[1]
+}
+
+// newRequestID generates a random, hex-encoded request ID.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,38 @@
+package ctxslog
+
+import (
+	"context"
+
+	"github.com/authzed/ctxkey"
+)
+
+// Defaulting adapts a ctxkey.DefaultingKey for use with KeyAttr. Since a
+// DefaultingKey always has a value (falling back to its default), the
+// resulting KeyReader never reports a missing value.
+func Defaulting[V comparable](k *ctxkey.DefaultingKey[V]) KeyReader[V] {
+	return defaultingReader[V]{key: k}
+}
+
+type defaultingReader[V comparable] struct {
+	key *ctxkey.DefaultingKey[V]
+}
+
+func (d defaultingReader[V]) Value(ctx context.Context) (V, bool) {
+	return d.key.Value(ctx), true
+}
+
+// Boxed adapts a ctxkey.BoxedKey for use with KeyAttr. The value is read
+// from the box each time a record is handled, so a box filled in later by
+// a handler further down the chain is reflected in log calls that happen
+// after it's set.
+func Boxed[V any](k *ctxkey.BoxedKey[V]) KeyReader[V] {
+	return boxedReader[V]{key: k}
+}
+
+type boxedReader[V any] struct {
+	key *ctxkey.BoxedKey[V]
+}
+
+func (b boxedReader[V]) Value(ctx context.Context) (V, bool) {
+	return b.key.Value(ctx), true
+}
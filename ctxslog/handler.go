@@ -0,0 +1,74 @@
+// Package ctxslog provides a slog.Handler middleware that injects values
+// stored via ctxkey into every log record, so that callers don't have to
+// repeat `logger.With(...)` boilerplate in each handler.
+package ctxslog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// KeyReader is the interface required of a key to be registered with
+// KeyAttr. ctxkey.Key and ctxkey.ValueKey already satisfy it; use Defaulting
+// or Boxed to adapt a ctxkey.DefaultingKey or ctxkey.BoxedKey.
+type KeyReader[V any] interface {
+	Value(ctx context.Context) (V, bool)
+}
+
+// Attr is a registered mapping from a context value to a slog.Attr. Build
+// one with KeyAttr and pass it to NewHandler.
+type Attr struct {
+	attr func(ctx context.Context) (slog.Attr, bool)
+}
+
+// KeyAttr builds an Attr that reads the value for k out of the context and
+// converts it to a slog.Value with fn. If k has no value in the context,
+// the attribute is omitted from the log record.
+func KeyAttr[V any](name string, k KeyReader[V], fn func(V) slog.Value) Attr {
+	return Attr{
+		attr: func(ctx context.Context) (slog.Attr, bool) {
+			v, ok := k.Value(ctx)
+			if !ok {
+				return slog.Attr{}, false
+			}
+			return slog.Attr{Key: name, Value: fn(v)}, true
+		},
+	}
+}
+
+// Handler is a slog.Handler middleware that adds a fixed set of Attrs,
+// read from the record's context, to every record handled by inner.
+type Handler struct {
+	inner slog.Handler
+	attrs []Attr
+}
+
+// NewHandler wraps inner with a Handler that adds attrs to every record.
+func NewHandler(inner slog.Handler, attrs ...Attr) *Handler {
+	return &Handler{inner: inner, attrs: attrs}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	for _, a := range h.attrs {
+		if attr, ok := a.attr(ctx); ok {
+			record.AddAttrs(attr)
+		}
+	}
+	return h.inner.Handle(ctx, record)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{inner: h.inner.WithAttrs(attrs), attrs: h.attrs}
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{inner: h.inner.WithGroup(name), attrs: h.attrs}
+}
@@ -0,0 +1,56 @@
+package ctxslog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/authzed/ctxkey"
+)
+
+func TestHandler(t *testing.T) {
+	ctxUser := ctxkey.New[string]()
+	ctxTenant := ctxkey.NewWithDefault("unknown")
+	ctxBytesWritten := ctxkey.NewBoxedWithDefault(0)
+
+	var buf bytes.Buffer
+	inner := slog.NewTextHandler(&buf, nil)
+	handler := NewHandler(inner,
+		KeyAttr("user", ctxUser, func(v string) slog.Value { return slog.StringValue(v) }),
+		KeyAttr("tenant", Defaulting(ctxTenant), func(v string) slog.Value { return slog.StringValue(v) }),
+		KeyAttr("bytes_written", Boxed(ctxBytesWritten), func(v int) slog.Value { return slog.IntValue(v) }),
+	)
+	logger := slog.New(handler)
+
+	ctx := ctxBytesWritten.SetBox(context.Background())
+	logger.InfoContext(ctx, "before user is set")
+
+	ctx = ctxUser.Set(ctx, "alice")
+	ctx = ctxTenant.Set(ctx, "acme")
+	ctxBytesWritten.Set(ctx, 42)
+	logger.InfoContext(ctx, "after user is set")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d", len(lines))
+	}
+
+	if strings.Contains(lines[0], "user=") {
+		t.Fatal("expected missing user to be omitted from first line")
+	}
+	if !strings.Contains(lines[0], "bytes_written=0") {
+		t.Fatalf("expected default box value to still be reported for first line, got %q", lines[0])
+	}
+
+	if !strings.Contains(lines[1], "user=alice") {
+		t.Fatalf("expected user=alice in second line, got %q", lines[1])
+	}
+	if !strings.Contains(lines[1], "tenant=acme") {
+		t.Fatalf("expected tenant=acme in second line, got %q", lines[1])
+	}
+	if !strings.Contains(lines[1], "bytes_written=42") {
+		t.Fatalf("expected bytes_written=42 in second line, got %q", lines[1])
+	}
+}
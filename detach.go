@@ -0,0 +1,99 @@
+package ctxkey
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// detachOptions controls how BoxedKey values are carried across by Detach
+// and Inherit.
+type detachOptions struct {
+	shareBoxes bool
+}
+
+// DetachOption configures the behavior of Detach and Inherit.
+type DetachOption func(*detachOptions)
+
+// WithSharedBoxes makes Detach and Inherit copy BoxedKey values by sharing
+// the same *Box handle, so later writes to the box in either context are
+// visible through the other.
+func WithSharedBoxes() DetachOption {
+	return func(o *detachOptions) {
+		o.shareBoxes = true
+	}
+}
+
+// WithClonedBoxes makes Detach and Inherit copy BoxedKey values into a new,
+// independent *Box handle. This is the default behavior.
+func WithClonedBoxes() DetachOption {
+	return func(o *detachOptions) {
+		o.shareBoxes = false
+	}
+}
+
+// copyFunc copies the value registered for a single key from src to dst,
+// returning the (possibly unchanged) dst.
+type copyFunc func(src, dst context.Context, opts detachOptions) context.Context
+
+// valueFunc returns the current value of a single key in ctx, and whether
+// it was actually set (as opposed to a reported default).
+type valueFunc func(ctx context.Context) (value any, set bool)
+
+// registeredKey is the bookkeeping this package keeps for every key created
+// through New, NewWithDefault, or NewBoxedWithDefault, to support Detach,
+// Inherit, and the introspection helpers in introspect.go.
+type registeredKey struct {
+	name  string
+	typ   reflect.Type
+	copy  copyFunc
+	value valueFunc
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []registeredKey
+)
+
+// registerKey records the bookkeeping for a key created by New,
+// NewWithDefault, or NewBoxedWithDefault.
+func registerKey(rk registeredKey) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, rk)
+}
+
+// snapshotRegistry returns a stable copy of the registry to iterate over
+// without holding registryMu.
+func snapshotRegistry() []registeredKey {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	rks := make([]registeredKey, len(registry))
+	copy(rks, registry)
+	return rks
+}
+
+// Inherit returns a copy of child with the value of every key created by
+// this package's constructors copied over from parent, so that parent's
+// cancellation and deadline don't apply to child, but its ctxkey values do.
+func Inherit(parent, child context.Context, opts ...DetachOption) context.Context {
+	var o detachOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	for _, rk := range snapshotRegistry() {
+		child = rk.copy(parent, child, o)
+	}
+	return child
+}
+
+// Detach returns a new context whose cancellation and deadline are
+// decoupled from ctx (similar to context.WithoutCancel), but which
+// preserves the values stored under every key created by this package.
+// It's useful when spawning a background goroutine from a request handler
+// that must retain values like a logger or tenant ID without re-plumbing
+// every key by hand.
+func Detach(ctx context.Context, opts ...DetachOption) context.Context {
+	return Inherit(ctx, context.Background(), opts...)
+}
@@ -0,0 +1,87 @@
+package ctxkey
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDetachPreservesValues(t *testing.T) {
+	ctxUser := New[string]()
+	ctxTenant := NewWithDefault("unknown")
+	ctxBytesWritten := NewBoxedWithDefault(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = ctxUser.Set(ctx, "alice")
+	ctx = ctxTenant.Set(ctx, "acme")
+	ctx = ctxBytesWritten.SetBox(ctx)
+	ctxBytesWritten.Set(ctx, 42)
+
+	detached := Detach(ctx)
+	cancel()
+
+	if err := ctx.Err(); err == nil {
+		t.Fatal("expected source context to be canceled")
+	}
+	if err := detached.Err(); err != nil {
+		t.Fatalf("expected detached context to be unaffected by cancellation, got %v", err)
+	}
+
+	if ctxUser.MustValue(detached) != "alice" {
+		t.Fatal("expected user to be preserved")
+	}
+	if ctxTenant.MustNonEmptyValue(detached) != "acme" {
+		t.Fatal("expected tenant to be preserved")
+	}
+	if ctxBytesWritten.Value(detached) != 42 {
+		t.Fatal("expected boxed value to be preserved")
+	}
+}
+
+func TestDetachClonesBoxesByDefault(t *testing.T) {
+	ctxBytesWritten := NewBoxedWithDefault(0)
+
+	ctx := ctxBytesWritten.SetBox(context.Background())
+	ctxBytesWritten.Set(ctx, 1)
+
+	detached := Detach(ctx)
+	ctxBytesWritten.Set(detached, 2)
+
+	if ctxBytesWritten.Value(ctx) != 1 {
+		t.Fatal("expected source box to be unaffected by writes to the cloned box")
+	}
+	if ctxBytesWritten.Value(detached) != 2 {
+		t.Fatal("expected detached box to hold its own write")
+	}
+}
+
+func TestDetachSharesBoxesWhenRequested(t *testing.T) {
+	ctxBytesWritten := NewBoxedWithDefault(0)
+
+	ctx := ctxBytesWritten.SetBox(context.Background())
+	ctxBytesWritten.Set(ctx, 1)
+
+	detached := Detach(ctx, WithSharedBoxes())
+	ctxBytesWritten.Set(detached, 2)
+
+	if ctxBytesWritten.Value(ctx) != 2 {
+		t.Fatal("expected source box to observe writes through the shared handle")
+	}
+}
+
+func TestInheritObservesChildsOwnCancellation(t *testing.T) {
+	ctxUser := New[string]()
+
+	parent := ctxUser.Set(context.Background(), "alice")
+	child, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inherited := Inherit(parent, child)
+	if ctxUser.MustValue(inherited) != "alice" {
+		t.Fatal("expected value from parent to be inherited")
+	}
+
+	cancel()
+	if inherited.Err() == nil {
+		t.Fatal("expected inherited context to still observe child's own cancellation")
+	}
+}
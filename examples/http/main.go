@@ -1,6 +1,7 @@
 // This is a simple example of using ctxkey with http middleware.
-// It demonstrates all three types of keys: normal, default, and boxed
-// and the ways they can be used to pass values through a chain of middleware.
+// It demonstrates all three types of keys: normal, default, and boxed,
+// and how the ctxhttp package packages up the request ID and response
+// accounting middleware that used to be hand-rolled here.
 
 package main
 
@@ -10,6 +11,7 @@ import (
 	"net/http"
 
 	"github.com/authzed/ctxkey"
+	"github.com/authzed/ctxkey/ctxhttp"
 )
 
 type User struct {
@@ -25,17 +27,15 @@ var (
 
 	// ctxLogger is a context key that stores a slog.Logger, but will return a default logger if unset
 	ctxLogger = ctxkey.NewWithDefault(slog.Default())
-
-	// ctxBytesWritten is a context key that stores the number of bytes written by a handler
-	// the NewBoxedWithDefault type is used when "decorating". In this case, a handler will
-	// fill in the value lower down the chain to be read by a wrapping middleware.
-	ctxBytesWritten = ctxkey.NewBoxedWithDefault(0)
 )
 
-// middleware fills in the user key after authorization
+// authorizeUserMiddleware fills in the user key after authorization
 func authorizeUserMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctxLogger.MustNonEmptyValue(r.Context()).Info("authorizing user", "name", name)
+		ctxLogger.MustNonEmptyValue(r.Context()).Info("authorizing user",
+			"name", name,
+			"request_id", ctxhttp.RequestID.Value(r.Context()),
+		)
 
 		user := &User{ID: 1, Name: name}
 		r = r.WithContext(ctxAuthorizedUser.Set(r.Context(), *user))
@@ -43,18 +43,16 @@ func authorizeUserMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// bytesWrittenLoggingMiddleware logs how many bytes were written by a handler
-func bytesWrittenLoggingMiddleware(next http.Handler) http.Handler {
+// accessLogMiddleware logs the outcome of a request, once ctxhttp's
+// middleware has filled in the byte count and status code.
+func accessLogMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// carve out a spot in the context for the value to be written
-		r = r.WithContext(ctxBytesWritten.SetBox(r.Context()))
-
-		// run the wrapped handler
 		next.ServeHTTP(w, r)
 
-		// extract the value from the context and log it
-		bytesWritten := ctxBytesWritten.Value(r.Context())
-		ctxLogger.MustNonEmptyValue(r.Context()).Info("wrote response", "bytes", bytesWritten)
+		ctxLogger.MustNonEmptyValue(r.Context()).Info("wrote response",
+			"status", ctxhttp.StatusCode.Value(r.Context()),
+			"bytes", ctxhttp.BytesWritten.Value(r.Context()),
+		)
 	})
 }
 
@@ -62,13 +60,9 @@ var helloHandler http.HandlerFunc = func(w http.ResponseWriter, req *http.Reques
 	// get user from context, will panic if missing
 	user := ctxAuthorizedUser.MustValue(req.Context())
 
-	written, err := fmt.Fprintf(w, "hello %s\n", user.Name)
-	if err != nil {
+	if _, err := fmt.Fprintf(w, "hello %s\n", user.Name); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
-
-	// fill in the "box" that is already present in the ctx
-	ctxBytesWritten.Set(req.Context(), written)
 }
 
 func main() {
@@ -78,8 +72,12 @@ func main() {
 	mux.Handle("/hello", helloHandler)
 
 	// install middleware
-	handler := bytesWrittenLoggingMiddleware(mux)
-	handler = authorizeUserMiddleware(handler)
+	handler := ctxhttp.Chain(
+		ctxhttp.RequestIDMiddleware,
+		ctxhttp.BytesWrittenMiddleware,
+		accessLogMiddleware,
+		authorizeUserMiddleware,
+	)(mux)
 
 	// serve
 	if err := http.ListenAndServe(":8090", handler); err != nil {
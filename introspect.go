@@ -0,0 +1,99 @@
+package ctxkey
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Option configures a key created by New, NewWithDefault, or
+// NewBoxedWithDefault.
+type Option func(*keyOptions)
+
+type keyOptions struct {
+	name string
+}
+
+// WithName gives a key a stable, human-readable name, used by Snapshot and
+// Dump instead of the key's Go type.
+func WithName(name string) Option {
+	return func(o *keyOptions) {
+		o.name = name
+	}
+}
+
+// resolveName applies opts and returns the resulting name, defaulting to
+// the key's Go type (e.g. "*ctxkey.Key[string]") if WithName wasn't used.
+func resolveName(opts []Option, k any) string {
+	var o keyOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.name != "" {
+		return o.name
+	}
+	return fmt.Sprintf("%T", k)
+}
+
+// Entry describes the current state of a single key registered through
+// this package's constructors, as returned by Snapshot.
+type Entry struct {
+	// Name is the key's name, set via WithName or defaulted to its Go type.
+	Name string
+	// Type is the type of value the key stores.
+	Type reflect.Type
+	// Value is the key's current value, or nil if Set is false.
+	Value any
+	// Set reports whether a value has actually been set for this key in
+	// the context, as opposed to Value holding a reported default.
+	Set bool
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name  string `json:"name"`
+		Type  string `json:"type"`
+		Value any    `json:"value,omitempty"`
+		Set   bool   `json:"set"`
+	}{
+		Name:  e.Name,
+		Type:  e.Type.String(),
+		Value: e.Value,
+		Set:   e.Set,
+	})
+}
+
+// Snapshot returns an Entry for every key created through this package's
+// constructors, describing its current value in ctx. This is primarily
+// intended for diagnosing "why is this value missing from context" bugs.
+func Snapshot(ctx context.Context) []Entry {
+	rks := snapshotRegistry()
+	entries := make([]Entry, len(rks))
+	for i, rk := range rks {
+		value, set := rk.value(ctx)
+		entries[i] = Entry{
+			Name:  rk.name,
+			Type:  rk.typ,
+			Value: value,
+			Set:   set,
+		}
+	}
+	return entries
+}
+
+// Dump formats Snapshot(ctx) as a multi-line string suitable for logging.
+func Dump(ctx context.Context) string {
+	entries := Snapshot(ctx)
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		if e.Set {
+			lines[i] = fmt.Sprintf("%s (%s) = %v", e.Name, e.Type, e.Value)
+		} else {
+			lines[i] = fmt.Sprintf("%s (%s) = <unset>", e.Name, e.Type)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
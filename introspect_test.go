@@ -0,0 +1,97 @@
+package ctxkey
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotAndDump(t *testing.T) {
+	ctxUser := New[string](WithName("authorized_user"))
+	ctxTenant := NewWithDefault("unknown", WithName("tenant"))
+	ctxBytesWritten := NewBoxedWithDefault(0, WithName("bytes_written"))
+
+	ctx := ctxUser.Set(context.Background(), "alice")
+	ctx = ctxBytesWritten.SetBox(ctx)
+	ctxBytesWritten.Set(ctx, 42)
+
+	if ctxTenant.Value(ctx) != "unknown" {
+		t.Fatal("expected default tenant")
+	}
+
+	entries := Snapshot(ctx)
+
+	byName := make(map[string]Entry, len(entries))
+	for _, e := range entries {
+		byName[e.Name] = e
+	}
+
+	user, ok := byName["authorized_user"]
+	if !ok || !user.Set || user.Value != "alice" {
+		t.Fatalf("expected authorized_user to be set to alice, got %+v", user)
+	}
+
+	tenant, ok := byName["tenant"]
+	if !ok || tenant.Set {
+		t.Fatalf("expected tenant to be unset, got %+v", tenant)
+	}
+
+	bytesWritten, ok := byName["bytes_written"]
+	if !ok || !bytesWritten.Set || bytesWritten.Value != 42 {
+		t.Fatalf("expected bytes_written to be set to 42, got %+v", bytesWritten)
+	}
+
+	dump := Dump(ctx)
+	if !strings.Contains(dump, "authorized_user") || !strings.Contains(dump, "alice") {
+		t.Fatalf("expected dump to mention authorized_user and alice, got %q", dump)
+	}
+	if !strings.Contains(dump, "tenant") || !strings.Contains(dump, "<unset>") {
+		t.Fatalf("expected dump to mark tenant as unset, got %q", dump)
+	}
+}
+
+func TestKeyNameDefaultsToType(t *testing.T) {
+	ctxUser := New[string]()
+	entries := Snapshot(ctxUser.Set(context.Background(), "alice"))
+
+	var found bool
+	for _, e := range entries {
+		if e.Value == "alice" {
+			found = true
+			if !strings.Contains(e.Name, "Key[string]") {
+				t.Fatalf("expected default name to mention the key's type, got %q", e.Name)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected to find the entry for ctxUser")
+	}
+}
+
+func TestEntryMarshalJSON(t *testing.T) {
+	entry := Entry{Name: "authorized_user", Type: reflect.TypeOf(""), Value: "alice", Set: true}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded["name"] != "authorized_user" {
+		t.Fatalf("expected name authorized_user, got %v", decoded["name"])
+	}
+	if decoded["type"] != "string" {
+		t.Fatalf("expected type string, got %v", decoded["type"])
+	}
+	if decoded["value"] != "alice" {
+		t.Fatalf("expected value alice, got %v", decoded["value"])
+	}
+	if decoded["set"] != true {
+		t.Fatalf("expected set true, got %v", decoded["set"])
+	}
+}
@@ -0,0 +1,97 @@
+package ctxkey
+
+import (
+	"context"
+	"testing"
+)
+
+// Key, DefaultingKey, and BoxedKey must never be zero-sized: Go's allocator
+// gives every zero-sized value the same address, so two independently
+// constructed keys of the same V would otherwise be the *same* context key,
+// silently clobbering each other's stored values.
+func TestKeyIdentityDoesNotCollide(t *testing.T) {
+	k1 := New[string]()
+	k2 := New[string]()
+
+	ctx := context.Background()
+	ctx = k1.Set(ctx, "k1-value")
+	ctx = k2.Set(ctx, "k2-value")
+
+	if v := k1.MustValue(ctx); v != "k1-value" {
+		t.Fatalf("expected k1 to keep its own value, got %q", v)
+	}
+	if v := k2.MustValue(ctx); v != "k2-value" {
+		t.Fatalf("expected k2 to keep its own value, got %q", v)
+	}
+}
+
+func TestDefaultingKeyIdentityDoesNotCollide(t *testing.T) {
+	k1 := NewWithDefault("")
+	k2 := NewWithDefault("")
+
+	ctx := context.Background()
+	ctx = k1.Set(ctx, "k1-value")
+	ctx = k2.Set(ctx, "k2-value")
+
+	if v := k1.Value(ctx); v != "k1-value" {
+		t.Fatalf("expected k1 to keep its own value, got %q", v)
+	}
+	if v := k2.Value(ctx); v != "k2-value" {
+		t.Fatalf("expected k2 to keep its own value, got %q", v)
+	}
+}
+
+func TestBoxedKeyIdentityDoesNotCollide(t *testing.T) {
+	k1 := NewBoxedWithDefault(0)
+	k2 := NewBoxedWithDefault(0)
+
+	ctx := context.Background()
+	ctx = k1.SetBox(ctx)
+	ctx = k2.SetBox(ctx)
+	k1.Set(ctx, 1)
+	k2.Set(ctx, 2)
+
+	if v := k1.Value(ctx); v != 1 {
+		t.Fatalf("expected k1 to keep its own value, got %d", v)
+	}
+	if v := k2.Value(ctx); v != 2 {
+		t.Fatalf("expected k2 to keep its own value, got %d", v)
+	}
+}
+
+// unit is a zero-sized type. Before DefaultingKey and BoxedKey carried a
+// guard field, a V like this made the whole struct zero-sized too (the
+// guard byte is the only field that can't disappear), which is exactly the
+// case TestDefaultingKeyIdentityDoesNotCollide and
+// TestBoxedKeyIdentityDoesNotCollide above fail to exercise, since string
+// and int default values already keep those structs non-zero-sized on
+// their own.
+type unit struct{}
+
+func TestDefaultingKeyIdentityDoesNotCollideZeroSizedV(t *testing.T) {
+	k1 := NewWithDefault(unit{})
+	k2 := NewWithDefault(unit{})
+
+	ctx := k1.Set(context.Background(), unit{})
+
+	if _, ok := k1.valueOk(ctx); !ok {
+		t.Fatal("expected k1 to have a value set")
+	}
+	if _, ok := k2.valueOk(ctx); ok {
+		t.Fatal("expected k2 to remain unset after only k1 was set")
+	}
+}
+
+func TestBoxedKeyIdentityDoesNotCollideZeroSizedV(t *testing.T) {
+	k1 := NewBoxedWithDefault(unit{})
+	k2 := NewBoxedWithDefault(unit{})
+
+	ctx := k1.SetBox(context.Background())
+
+	if _, ok := k1.box(ctx); !ok {
+		t.Fatal("expected k1 to have a box")
+	}
+	if _, ok := k2.box(ctx); ok {
+		t.Fatal("expected k2 to remain unboxed after only k1's box was set")
+	}
+}